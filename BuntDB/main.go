@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	socketio "github.com/googollee/go-socket.io"
@@ -11,37 +15,37 @@ import (
 )
 
 func main() {
-	// Create a new Socket.IO server
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// Opened once for the server's lifetime instead of inside the "update"
+	// handler, so every transaction shares the same data instead of each
+	// starting from an empty :memory: db.
+	db, err := buntdb.Open(":memory:")
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	server, err := socketio.NewServer(nil)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Handle connection events
 	server.OnConnect("/", func(s socketio.Conn) error {
 		s.SetContext("")
 		fmt.Println("New connection:", s.ID())
 		return nil
 	})
 
-	// Handle disconnection events
 	server.OnDisconnect("/", func(s socketio.Conn, reason string) {
 		fmt.Println("Connection closed:", s.ID(), reason)
 	})
 
-	// Handle transactions
 	server.OnEvent("/", "update", func(s socketio.Conn, txData string) {
 		fmt.Println("Received update request:", txData)
 
-		// Open the data.db file. It will be created if it doesn't exist.
-		db, err := buntdb.Open(":memory:")
-		if err != nil {
-			log.Fatal(err)
-		}
-		defer db.Close()
-
 		start := time.Now()
-		err = db.Update(func(tx *buntdb.Tx) error {
+		err := db.Update(func(tx *buntdb.Tx) error {
 			// Perform transaction here based on txData
 			return nil
 		})
@@ -51,15 +55,44 @@ func main() {
 		}
 		elapsed := time.Since(start)
 
-		// Emit performance measurement
 		s.Emit("updateResult", fmt.Sprintf("Update transaction took: %s", elapsed))
 	})
 
-	// Serve the Socket.IO server at /socket.io endpoint
-	http.Handle("/socket.io/", server)
-	http.Handle("/", http.FileServer(http.Dir("./public")))
+	mux := http.NewServeMux()
+	mux.Handle("/socket.io/", server)
+	mux.Handle("/", http.FileServer(http.Dir("./public")))
+	httpServer := &http.Server{Addr: ":3001", Handler: mux}
+
+	errc := make(chan error, 2)
+	go func() { errc <- server.Serve() }()
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errc <- err
+			return
+		}
+		errc <- nil
+	}()
 
-	// Start the HTTP server
 	fmt.Println("Server started at :3001")
-	log.Fatal(http.ListenAndServe(":3001", nil))
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-errc:
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && runErr == nil {
+		runErr = err
+	}
+	if err := server.Close(); err != nil && runErr == nil {
+		runErr = err
+	}
+	if err := db.Close(); err != nil && runErr == nil {
+		runErr = err
+	}
+	if runErr != nil && runErr != ctx.Err() {
+		log.Fatal(runErr)
+	}
 }
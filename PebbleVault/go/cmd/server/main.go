@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"pebble-vault/grpcsrv"
+	"pebble-vault/server"
+	"pebble-vault/store"
+)
+
+// storageBackend reads the store.Open backend name from
+// PEBBLE_VAULT_BACKEND, defaulting to buntdb. This binary also serves
+// gRPC, which needs buntdb, so any other backend (such as bbolt) makes
+// main exit at startup.
+func storageBackend() string {
+	if b := os.Getenv("PEBBLE_VAULT_BACKEND"); b != "" {
+		return b
+	}
+	return store.BackendBuntDB
+}
+
+// storagePath is where storageBackend's db lives on disk, or ":memory:"
+// for a non-persistent store. See storageBackend for the equivalent
+// backend knob.
+func storagePath() string {
+	if p := os.Getenv("PEBBLE_VAULT_PATH"); p != "" {
+		return p
+	}
+	return ":memory:"
+}
+
+func main() {
+	fmt.Println("Start server...")
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	backend := storageBackend()
+	db, err := store.Open(backend, storagePath())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv, err := server.New(":3001", db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// grpcsrv only understands buntdb directly (see grpcsrv.NewServer), so
+	// it shares the Socket.IO server's own *buntdb.DB instead of opening a
+	// second, disconnected database: a galaxy created over one transport
+	// needs to be visible on the other.
+	buntStore, ok := db.(*store.BuntStore)
+	if !ok {
+		log.Fatalf("gRPC front end requires the %s backend, got %q", store.BackendBuntDB, backend)
+	}
+
+	errc := make(chan error, 2)
+	go func() { errc <- srv.Run(ctx) }()
+	go func() {
+		errc <- grpcsrv.Listen(ctx, grpcsrv.NewServer(buntStore.Underlying()), ":7443", "/tmp/pebble-vault.sock")
+	}()
+
+	log.Println("Serving at localhost:3001...")
+	for i := 0; i < cap(errc); i++ {
+		if err := <-errc; err != nil && err != ctx.Err() {
+			log.Printf("server error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package grpcsrv
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"pebble-vault/grpcsrv/pb"
+)
+
+// Client is a thin wrapper around the generated gRPC client so callers
+// don't need to import the pb package directly or manage the connection.
+type Client struct {
+	conn *grpc.ClientConn
+	pb.SpatialVaultClient
+}
+
+// Dial connects to a SpatialVault server over TCP, e.g. "localhost:7443".
+func Dial(target string) (*Client, error) {
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, SpatialVaultClient: pb.NewSpatialVaultClient(conn)}, nil
+}
+
+// DialUnix connects to a SpatialVault server over a Unix domain socket.
+func DialUnix(socketPath string) (*Client, error) {
+	conn, err := grpc.NewClient("unix:"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, SpatialVaultClient: pb.NewSpatialVaultClient(conn)}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,224 @@
+// Hand-written to match pebblevault.proto's wire format, not real
+// protoc-gen-go output: these types only implement the legacy v1
+// Reset/String/ProtoMessage trio (via gRPC's v1-adapter shim), not
+// protoreflect.ProtoMessage, and they lack the descriptor bytes real
+// generated code carries. Running protoc-gen-go against pebblevault.proto
+// will produce different, incompatible types and silently drop
+// hand-added helpers like Point.RectString() that grpcsrv/server.go
+// depends on — update this file by hand instead.
+// source: pebblevault.proto
+
+package pb
+
+import (
+	fmt "fmt"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+type Transform struct {
+	Location *Vector3     `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Rotation *Vector3     `protobuf:"bytes,2,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	Scale    *Vector3     `protobuf:"bytes,3,opt,name=scale,proto3" json:"scale,omitempty"`
+	Points   []*PointData `protobuf:"bytes,4,rep,name=points,proto3" json:"points,omitempty"`
+}
+
+func (m *Transform) Reset()         { *m = Transform{} }
+func (m *Transform) String() string { return proto.CompactTextString(m) }
+func (*Transform) ProtoMessage()    {}
+
+type Vector3 struct {
+	X float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Z float64 `protobuf:"fixed64,3,opt,name=z,proto3" json:"z,omitempty"`
+}
+
+func (m *Vector3) Reset()         { *m = Vector3{} }
+func (m *Vector3) String() string { return proto.CompactTextString(m) }
+func (*Vector3) ProtoMessage()    {}
+
+type Color struct {
+	R uint32 `protobuf:"varint,1,opt,name=r,proto3" json:"r,omitempty"`
+	G uint32 `protobuf:"varint,2,opt,name=g,proto3" json:"g,omitempty"`
+	B uint32 `protobuf:"varint,3,opt,name=b,proto3" json:"b,omitempty"`
+}
+
+func (m *Color) Reset()         { *m = Color{} }
+func (m *Color) String() string { return proto.CompactTextString(m) }
+func (*Color) ProtoMessage()    {}
+
+type PointData struct {
+	Position   *Vector3 `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	Brightness float64  `protobuf:"fixed64,2,opt,name=brightness,proto3" json:"brightness,omitempty"`
+	Color      *Color   `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+}
+
+func (m *PointData) Reset()         { *m = PointData{} }
+func (m *PointData) String() string { return proto.CompactTextString(m) }
+func (*PointData) ProtoMessage()    {}
+
+type Point struct {
+	X float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (m *Point) Reset()         { *m = Point{} }
+func (m *Point) String() string { return proto.CompactTextString(m) }
+func (*Point) ProtoMessage()    {}
+
+// RectString renders the point the same way buntdb's spatial index expects it
+// on the wire, e.g. "[-115.567 33.532]".
+func (m *Point) RectString() string {
+	if m == nil {
+		return "[]"
+	}
+	return fmt.Sprintf("[%v %v]", m.X, m.Y)
+}
+
+type Rect struct {
+	Min *Point `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max *Point `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (m *Rect) Reset()         { *m = Rect{} }
+func (m *Rect) String() string { return proto.CompactTextString(m) }
+func (*Rect) ProtoMessage()    {}
+
+type CreateSpatialIndexRequest struct {
+	IndexName       string `protobuf:"bytes,1,opt,name=index_name,json=indexName,proto3" json:"index_name,omitempty"`
+	IndexKeyPattern string `protobuf:"bytes,2,opt,name=index_key_pattern,json=indexKeyPattern,proto3" json:"index_key_pattern,omitempty"`
+}
+
+func (m *CreateSpatialIndexRequest) Reset()         { *m = CreateSpatialIndexRequest{} }
+func (m *CreateSpatialIndexRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSpatialIndexRequest) ProtoMessage()    {}
+
+type CreateSpatialIndexResponse struct{}
+
+func (m *CreateSpatialIndexResponse) Reset()         { *m = CreateSpatialIndexResponse{} }
+func (m *CreateSpatialIndexResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateSpatialIndexResponse) ProtoMessage()    {}
+
+type CreateGalaxyRequest struct {
+	Key       string     `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Position  *Point     `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	Transform *Transform `protobuf:"bytes,3,opt,name=transform,proto3" json:"transform,omitempty"`
+}
+
+func (m *CreateGalaxyRequest) Reset()         { *m = CreateGalaxyRequest{} }
+func (m *CreateGalaxyRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateGalaxyRequest) ProtoMessage()    {}
+
+type CreateGalaxyResponse struct{}
+
+func (m *CreateGalaxyResponse) Reset()         { *m = CreateGalaxyResponse{} }
+func (m *CreateGalaxyResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateGalaxyResponse) ProtoMessage()    {}
+
+type SetFleetPositionRequest struct {
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Position *Point `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+}
+
+func (m *SetFleetPositionRequest) Reset()         { *m = SetFleetPositionRequest{} }
+func (m *SetFleetPositionRequest) String() string { return proto.CompactTextString(m) }
+func (*SetFleetPositionRequest) ProtoMessage()    {}
+
+type SetFleetPositionResponse struct{}
+
+func (m *SetFleetPositionResponse) Reset()         { *m = SetFleetPositionResponse{} }
+func (m *SetFleetPositionResponse) String() string { return proto.CompactTextString(m) }
+func (*SetFleetPositionResponse) ProtoMessage()    {}
+
+type GetKNearestGalaxysRequest struct {
+	IndexName   string  `protobuf:"bytes,1,opt,name=index_name,json=indexName,proto3" json:"index_name,omitempty"`
+	Origin      *Point  `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	K           int32   `protobuf:"varint,3,opt,name=k,proto3" json:"k,omitempty"`
+	MaxDistance float64 `protobuf:"fixed64,4,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+}
+
+func (m *GetKNearestGalaxysRequest) Reset()         { *m = GetKNearestGalaxysRequest{} }
+func (m *GetKNearestGalaxysRequest) String() string { return proto.CompactTextString(m) }
+func (*GetKNearestGalaxysRequest) ProtoMessage()    {}
+
+type Neighbor struct {
+	Key      string  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value    string  `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Distance float64 `protobuf:"fixed64,3,opt,name=distance,proto3" json:"distance,omitempty"`
+}
+
+func (m *Neighbor) Reset()         { *m = Neighbor{} }
+func (m *Neighbor) String() string { return proto.CompactTextString(m) }
+func (*Neighbor) ProtoMessage()    {}
+
+type GetKNearestGalaxysResponse struct {
+	Neighbors []*Neighbor `protobuf:"bytes,1,rep,name=neighbors,proto3" json:"neighbors,omitempty"`
+}
+
+func (m *GetKNearestGalaxysResponse) Reset()         { *m = GetKNearestGalaxysResponse{} }
+func (m *GetKNearestGalaxysResponse) String() string { return proto.CompactTextString(m) }
+func (*GetKNearestGalaxysResponse) ProtoMessage()    {}
+
+type NearbyStreamRequest struct {
+	IndexName   string  `protobuf:"bytes,1,opt,name=index_name,json=indexName,proto3" json:"index_name,omitempty"`
+	Origin      *Point  `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	MaxDistance float64 `protobuf:"fixed64,3,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+}
+
+func (m *NearbyStreamRequest) Reset()         { *m = NearbyStreamRequest{} }
+func (m *NearbyStreamRequest) String() string { return proto.CompactTextString(m) }
+func (*NearbyStreamRequest) ProtoMessage()    {}
+
+type DeleteRequest struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()    {}
+
+type DeleteResponse struct{}
+
+func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
+func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteResponse) ProtoMessage()    {}
+
+type IterateRequest struct {
+	KeyPattern string `protobuf:"bytes,1,opt,name=key_pattern,json=keyPattern,proto3" json:"key_pattern,omitempty"`
+}
+
+func (m *IterateRequest) Reset()         { *m = IterateRequest{} }
+func (m *IterateRequest) String() string { return proto.CompactTextString(m) }
+func (*IterateRequest) ProtoMessage()    {}
+
+type IterateResponse struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *IterateResponse) Reset()         { *m = IterateResponse{} }
+func (m *IterateResponse) String() string { return proto.CompactTextString(m) }
+func (*IterateResponse) ProtoMessage()    {}
+
+func init() {
+	proto.RegisterType((*Transform)(nil), "pebblevault.Transform")
+	proto.RegisterType((*Vector3)(nil), "pebblevault.Vector3")
+	proto.RegisterType((*Color)(nil), "pebblevault.Color")
+	proto.RegisterType((*PointData)(nil), "pebblevault.PointData")
+	proto.RegisterType((*Point)(nil), "pebblevault.Point")
+	proto.RegisterType((*Rect)(nil), "pebblevault.Rect")
+	proto.RegisterType((*CreateSpatialIndexRequest)(nil), "pebblevault.CreateSpatialIndexRequest")
+	proto.RegisterType((*CreateSpatialIndexResponse)(nil), "pebblevault.CreateSpatialIndexResponse")
+	proto.RegisterType((*CreateGalaxyRequest)(nil), "pebblevault.CreateGalaxyRequest")
+	proto.RegisterType((*CreateGalaxyResponse)(nil), "pebblevault.CreateGalaxyResponse")
+	proto.RegisterType((*SetFleetPositionRequest)(nil), "pebblevault.SetFleetPositionRequest")
+	proto.RegisterType((*SetFleetPositionResponse)(nil), "pebblevault.SetFleetPositionResponse")
+	proto.RegisterType((*GetKNearestGalaxysRequest)(nil), "pebblevault.GetKNearestGalaxysRequest")
+	proto.RegisterType((*Neighbor)(nil), "pebblevault.Neighbor")
+	proto.RegisterType((*GetKNearestGalaxysResponse)(nil), "pebblevault.GetKNearestGalaxysResponse")
+	proto.RegisterType((*NearbyStreamRequest)(nil), "pebblevault.NearbyStreamRequest")
+	proto.RegisterType((*DeleteRequest)(nil), "pebblevault.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "pebblevault.DeleteResponse")
+	proto.RegisterType((*IterateRequest)(nil), "pebblevault.IterateRequest")
+	proto.RegisterType((*IterateResponse)(nil), "pebblevault.IterateResponse")
+}
@@ -0,0 +1,324 @@
+// Hand-written to match pebblevault.proto's service definition, not real
+// protoc-gen-go-grpc output. See pebblevault.pb.go for why; keep this file
+// and that one in sync by hand when the .proto changes.
+// source: pebblevault.proto
+
+package pb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+const (
+	SpatialVault_CreateSpatialIndex_FullMethodName = "/pebblevault.SpatialVault/CreateSpatialIndex"
+	SpatialVault_CreateGalaxy_FullMethodName       = "/pebblevault.SpatialVault/CreateGalaxy"
+	SpatialVault_SetFleetPosition_FullMethodName   = "/pebblevault.SpatialVault/SetFleetPosition"
+	SpatialVault_GetKNearestGalaxys_FullMethodName = "/pebblevault.SpatialVault/GetKNearestGalaxys"
+	SpatialVault_NearbyStream_FullMethodName       = "/pebblevault.SpatialVault/NearbyStream"
+	SpatialVault_Delete_FullMethodName             = "/pebblevault.SpatialVault/Delete"
+	SpatialVault_Iterate_FullMethodName            = "/pebblevault.SpatialVault/Iterate"
+)
+
+// SpatialVaultClient is the client API for SpatialVault service.
+type SpatialVaultClient interface {
+	CreateSpatialIndex(ctx context.Context, in *CreateSpatialIndexRequest, opts ...grpc.CallOption) (*CreateSpatialIndexResponse, error)
+	CreateGalaxy(ctx context.Context, in *CreateGalaxyRequest, opts ...grpc.CallOption) (*CreateGalaxyResponse, error)
+	SetFleetPosition(ctx context.Context, in *SetFleetPositionRequest, opts ...grpc.CallOption) (*SetFleetPositionResponse, error)
+	GetKNearestGalaxys(ctx context.Context, in *GetKNearestGalaxysRequest, opts ...grpc.CallOption) (*GetKNearestGalaxysResponse, error)
+	NearbyStream(ctx context.Context, in *NearbyStreamRequest, opts ...grpc.CallOption) (SpatialVault_NearbyStreamClient, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	Iterate(ctx context.Context, in *IterateRequest, opts ...grpc.CallOption) (SpatialVault_IterateClient, error)
+}
+
+type spatialVaultClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSpatialVaultClient(cc grpc.ClientConnInterface) SpatialVaultClient {
+	return &spatialVaultClient{cc}
+}
+
+func (c *spatialVaultClient) CreateSpatialIndex(ctx context.Context, in *CreateSpatialIndexRequest, opts ...grpc.CallOption) (*CreateSpatialIndexResponse, error) {
+	out := new(CreateSpatialIndexResponse)
+	if err := c.cc.Invoke(ctx, SpatialVault_CreateSpatialIndex_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spatialVaultClient) CreateGalaxy(ctx context.Context, in *CreateGalaxyRequest, opts ...grpc.CallOption) (*CreateGalaxyResponse, error) {
+	out := new(CreateGalaxyResponse)
+	if err := c.cc.Invoke(ctx, SpatialVault_CreateGalaxy_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spatialVaultClient) SetFleetPosition(ctx context.Context, in *SetFleetPositionRequest, opts ...grpc.CallOption) (*SetFleetPositionResponse, error) {
+	out := new(SetFleetPositionResponse)
+	if err := c.cc.Invoke(ctx, SpatialVault_SetFleetPosition_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spatialVaultClient) GetKNearestGalaxys(ctx context.Context, in *GetKNearestGalaxysRequest, opts ...grpc.CallOption) (*GetKNearestGalaxysResponse, error) {
+	out := new(GetKNearestGalaxysResponse)
+	if err := c.cc.Invoke(ctx, SpatialVault_GetKNearestGalaxys_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spatialVaultClient) NearbyStream(ctx context.Context, in *NearbyStreamRequest, opts ...grpc.CallOption) (SpatialVault_NearbyStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SpatialVault_ServiceDesc.Streams[0], SpatialVault_NearbyStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &spatialVaultNearbyStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SpatialVault_NearbyStreamClient interface {
+	Recv() (*Neighbor, error)
+	grpc.ClientStream
+}
+
+type spatialVaultNearbyStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *spatialVaultNearbyStreamClient) Recv() (*Neighbor, error) {
+	m := new(Neighbor)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *spatialVaultClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	if err := c.cc.Invoke(ctx, SpatialVault_Delete_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spatialVaultClient) Iterate(ctx context.Context, in *IterateRequest, opts ...grpc.CallOption) (SpatialVault_IterateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SpatialVault_ServiceDesc.Streams[1], SpatialVault_Iterate_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &spatialVaultIterateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SpatialVault_IterateClient interface {
+	Recv() (*IterateResponse, error)
+	grpc.ClientStream
+}
+
+type spatialVaultIterateClient struct {
+	grpc.ClientStream
+}
+
+func (x *spatialVaultIterateClient) Recv() (*IterateResponse, error) {
+	m := new(IterateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SpatialVaultServer is the server API for SpatialVault service. Embed
+// UnimplementedSpatialVaultServer for forward compatibility.
+type SpatialVaultServer interface {
+	CreateSpatialIndex(context.Context, *CreateSpatialIndexRequest) (*CreateSpatialIndexResponse, error)
+	CreateGalaxy(context.Context, *CreateGalaxyRequest) (*CreateGalaxyResponse, error)
+	SetFleetPosition(context.Context, *SetFleetPositionRequest) (*SetFleetPositionResponse, error)
+	GetKNearestGalaxys(context.Context, *GetKNearestGalaxysRequest) (*GetKNearestGalaxysResponse, error)
+	NearbyStream(*NearbyStreamRequest, SpatialVault_NearbyStreamServer) error
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	Iterate(*IterateRequest, SpatialVault_IterateServer) error
+	mustEmbedUnimplementedSpatialVaultServer()
+}
+
+type UnimplementedSpatialVaultServer struct{}
+
+func (UnimplementedSpatialVaultServer) CreateSpatialIndex(context.Context, *CreateSpatialIndexRequest) (*CreateSpatialIndexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSpatialIndex not implemented")
+}
+func (UnimplementedSpatialVaultServer) CreateGalaxy(context.Context, *CreateGalaxyRequest) (*CreateGalaxyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGalaxy not implemented")
+}
+func (UnimplementedSpatialVaultServer) SetFleetPosition(context.Context, *SetFleetPositionRequest) (*SetFleetPositionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFleetPosition not implemented")
+}
+func (UnimplementedSpatialVaultServer) GetKNearestGalaxys(context.Context, *GetKNearestGalaxysRequest) (*GetKNearestGalaxysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKNearestGalaxys not implemented")
+}
+func (UnimplementedSpatialVaultServer) NearbyStream(*NearbyStreamRequest, SpatialVault_NearbyStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method NearbyStream not implemented")
+}
+func (UnimplementedSpatialVaultServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedSpatialVaultServer) Iterate(*IterateRequest, SpatialVault_IterateServer) error {
+	return status.Errorf(codes.Unimplemented, "method Iterate not implemented")
+}
+func (UnimplementedSpatialVaultServer) mustEmbedUnimplementedSpatialVaultServer() {}
+
+func RegisterSpatialVaultServer(s grpc.ServiceRegistrar, srv SpatialVaultServer) {
+	s.RegisterService(&SpatialVault_ServiceDesc, srv)
+}
+
+func _SpatialVault_CreateSpatialIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSpatialIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpatialVaultServer).CreateSpatialIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: SpatialVault_CreateSpatialIndex_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpatialVaultServer).CreateSpatialIndex(ctx, req.(*CreateSpatialIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpatialVault_CreateGalaxy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGalaxyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpatialVaultServer).CreateGalaxy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: SpatialVault_CreateGalaxy_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpatialVaultServer).CreateGalaxy(ctx, req.(*CreateGalaxyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpatialVault_SetFleetPosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFleetPositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpatialVaultServer).SetFleetPosition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: SpatialVault_SetFleetPosition_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpatialVaultServer).SetFleetPosition(ctx, req.(*SetFleetPositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpatialVault_GetKNearestGalaxys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKNearestGalaxysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpatialVaultServer).GetKNearestGalaxys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: SpatialVault_GetKNearestGalaxys_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpatialVaultServer).GetKNearestGalaxys(ctx, req.(*GetKNearestGalaxysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpatialVault_NearbyStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NearbyStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SpatialVaultServer).NearbyStream(m, &spatialVaultNearbyStreamServer{stream})
+}
+
+type SpatialVault_NearbyStreamServer interface {
+	Send(*Neighbor) error
+	grpc.ServerStream
+}
+
+type spatialVaultNearbyStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *spatialVaultNearbyStreamServer) Send(m *Neighbor) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SpatialVault_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpatialVaultServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: SpatialVault_Delete_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpatialVaultServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpatialVault_Iterate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IterateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SpatialVaultServer).Iterate(m, &spatialVaultIterateServer{stream})
+}
+
+type SpatialVault_IterateServer interface {
+	Send(*IterateResponse) error
+	grpc.ServerStream
+}
+
+type spatialVaultIterateServer struct {
+	grpc.ServerStream
+}
+
+func (x *spatialVaultIterateServer) Send(m *IterateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// SpatialVault_ServiceDesc is the grpc.ServiceDesc for SpatialVault service.
+var SpatialVault_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pebblevault.SpatialVault",
+	HandlerType: (*SpatialVaultServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "CreateSpatialIndex", Handler: _SpatialVault_CreateSpatialIndex_Handler},
+		{MethodName: "CreateGalaxy", Handler: _SpatialVault_CreateGalaxy_Handler},
+		{MethodName: "SetFleetPosition", Handler: _SpatialVault_SetFleetPosition_Handler},
+		{MethodName: "GetKNearestGalaxys", Handler: _SpatialVault_GetKNearestGalaxys_Handler},
+		{MethodName: "Delete", Handler: _SpatialVault_Delete_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "NearbyStream", Handler: _SpatialVault_NearbyStream_Handler, ServerStreams: true},
+		{StreamName: "Iterate", Handler: _SpatialVault_Iterate_Handler, ServerStreams: true},
+	},
+	Metadata: "pebblevault.proto",
+}
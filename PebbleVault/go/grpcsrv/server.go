@@ -0,0 +1,197 @@
+// Package grpcsrv exposes the buntdb-backed spatial vault over gRPC, as a
+// typed alternative to the Socket.IO handler in package server. Unlike the
+// cgo-facing exports in package main, Server owns its *buntdb.DB directly and
+// is meant to be embedded in a normal Go process (or driven from the
+// socket.io main alongside the Socket.IO listener).
+package grpcsrv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/tidwall/buntdb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pebble-vault/grpcsrv/pb"
+)
+
+// transformKey is where a galaxy's Transform is stored, alongside its
+// buntdb-indexable position at key itself. Keeping it a separate key
+// means CreateGalaxy's spatial index still sees a plain rect string, the
+// only format buntdb.IndexRect understands.
+func transformKey(key string) string {
+	return key + ":transform"
+}
+
+// Server implements pb.SpatialVaultServer on top of a single *buntdb.DB.
+type Server struct {
+	pb.UnimplementedSpatialVaultServer
+
+	db *buntdb.DB
+}
+
+// NewServer wraps an already-open buntdb.DB. The caller retains ownership
+// of db and is responsible for closing it.
+func NewServer(db *buntdb.DB) *Server {
+	return &Server{db: db}
+}
+
+// Listen starts a gRPC server bound to both a TCP address and a Unix
+// domain socket so in-process engines and remote ones can each use
+// whichever transport suits them. Either addr may be empty to skip that
+// listener. Listen blocks until ctx is cancelled or one of its listeners
+// stops serving.
+func Listen(ctx context.Context, srv *Server, tcpAddr, unixSockPath string) error {
+	grpcServer := grpc.NewServer()
+	pb.RegisterSpatialVaultServer(grpcServer, srv)
+
+	var listeners []net.Listener
+	if tcpAddr != "" {
+		lis, err := net.Listen("tcp", tcpAddr)
+		if err != nil {
+			return fmt.Errorf("grpcsrv: listen tcp %s: %w", tcpAddr, err)
+		}
+		listeners = append(listeners, lis)
+	}
+	if unixSockPath != "" {
+		os.Remove(unixSockPath)
+		lis, err := net.Listen("unix", unixSockPath)
+		if err != nil {
+			return fmt.Errorf("grpcsrv: listen unix %s: %w", unixSockPath, err)
+		}
+		listeners = append(listeners, lis)
+	}
+
+	errc := make(chan error, len(listeners))
+	for _, lis := range listeners {
+		lis := lis
+		go func() { errc <- grpcServer.Serve(lis) }()
+	}
+
+	select {
+	case <-ctx.Done():
+		grpcServer.GracefulStop()
+		return ctx.Err()
+	case err := <-errc:
+		grpcServer.GracefulStop()
+		return err
+	}
+}
+
+func (s *Server) CreateSpatialIndex(ctx context.Context, req *pb.CreateSpatialIndexRequest) (*pb.CreateSpatialIndexResponse, error) {
+	if err := s.db.CreateSpatialIndex(req.IndexName, req.IndexKeyPattern, buntdb.IndexRect); err != nil {
+		return nil, status.Errorf(codes.Internal, "create spatial index: %v", err)
+	}
+	return &pb.CreateSpatialIndexResponse{}, nil
+}
+
+func (s *Server) CreateGalaxy(ctx context.Context, req *pb.CreateGalaxyRequest) (*pb.CreateGalaxyResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		if _, _, err := tx.Set(req.Key, req.Position.RectString(), nil); err != nil {
+			return err
+		}
+		if req.Transform != nil {
+			data, err := json.Marshal(req.Transform)
+			if err != nil {
+				return fmt.Errorf("encode transform: %w", err)
+			}
+			if _, _, err := tx.Set(transformKey(req.Key), string(data), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "create galaxy: %v", err)
+	}
+	return &pb.CreateGalaxyResponse{}, nil
+}
+
+func (s *Server) SetFleetPosition(ctx context.Context, req *pb.SetFleetPositionRequest) (*pb.SetFleetPositionResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(req.Key, req.Position.RectString(), nil)
+		return err
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "set fleet position: %v", err)
+	}
+	return &pb.SetFleetPositionResponse{}, nil
+}
+
+func (s *Server) GetKNearestGalaxys(ctx context.Context, req *pb.GetKNearestGalaxysRequest) (*pb.GetKNearestGalaxysResponse, error) {
+	resp := &pb.GetKNearestGalaxysResponse{}
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var count int32
+		tx.Nearby(req.IndexName, req.Origin.RectString(), func(key, val string, dist float64) bool {
+			if req.MaxDistance > 0 && dist > req.MaxDistance {
+				return false
+			}
+			resp.Neighbors = append(resp.Neighbors, &pb.Neighbor{Key: key, Value: val, Distance: dist})
+			count++
+			return req.K <= 0 || count < req.K
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get k nearest galaxys: %v", err)
+	}
+	return resp, nil
+}
+
+func (s *Server) NearbyStream(req *pb.NearbyStreamRequest, stream pb.SpatialVault_NearbyStreamServer) error {
+	return s.db.View(func(tx *buntdb.Tx) error {
+		var sendErr error
+		tx.Nearby(req.IndexName, req.Origin.RectString(), func(key, val string, dist float64) bool {
+			if req.MaxDistance > 0 && dist > req.MaxDistance {
+				return false
+			}
+			sendErr = stream.Send(&pb.Neighbor{Key: key, Value: val, Distance: dist})
+			return sendErr == nil
+		})
+		return sendErr
+	})
+}
+
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(req.Key)
+		if err != nil {
+			return err
+		}
+		// Ignore ErrNotFound: most keys never had a Transform stored
+		// alongside them.
+		if _, err := tx.Delete(transformKey(req.Key)); err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+		return nil
+	})
+	if err == buntdb.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "key %q not found", req.Key)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete: %v", err)
+	}
+	return &pb.DeleteResponse{}, nil
+}
+
+func (s *Server) Iterate(req *pb.IterateRequest, stream pb.SpatialVault_IterateServer) error {
+	return s.db.View(func(tx *buntdb.Tx) error {
+		var sendErr error
+		tx.AscendKeys(req.KeyPattern, func(key, val string) bool {
+			sendErr = stream.Send(&pb.IterateResponse{Key: key, Value: val})
+			return sendErr == nil
+		})
+		return sendErr
+	})
+}
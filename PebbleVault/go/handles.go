@@ -0,0 +1,106 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"sync"
+
+	"pebble-vault/store"
+)
+
+// Exported error codes. C/C++/Rust callers check these before trusting a
+// zero-value return, then call LastError(handle) for the human-readable
+// reason.
+const (
+	errOK              int32 = 0
+	errInvalidHandle   int32 = -1
+	errOperationFailed int32 = -2
+)
+
+// invalidHandle is never issued by handleTable.add, so it doubles as the
+// "no db" sentinel CreateDB returns on failure and LastError can still be
+// queried against.
+const invalidHandle uint64 = 0
+
+// handleTable hands out opaque uint64 handles for store.Store values
+// instead of letting callers smuggle a raw *buntdb.DB across the cgo
+// boundary as a uintptr. Go's GC is free to move or collect anything an
+// unsafe.Pointer-turned-uintptr doesn't keep reachable, so returning the
+// converted integer and casting it back later (as this package used to)
+// is undefined behavior under a moving collector and trips
+// GODEBUG=cgocheck=2. Keeping the real store.Store alive in this map and
+// only ever exporting its key sidesteps that entirely.
+type handleTable struct {
+	mu   sync.Mutex
+	next uint64
+	dbs  map[uint64]store.Store
+	errs map[uint64]string
+}
+
+var handles = &handleTable{
+	dbs:  make(map[uint64]store.Store),
+	errs: make(map[uint64]string),
+}
+
+// add allocates a new handle for db and stores it. IDs are monotonically
+// increasing and never reused, so a handle from a closed db can't collide
+// with one that replaces it.
+func (t *handleTable) add(db store.Store) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next++
+	id := t.next
+	t.dbs[id] = db
+	return id
+}
+
+// get resolves a handle to its store.Store. ok is false for handle 0 or
+// any handle that was never issued or has since been closed.
+func (t *handleTable) get(handle uint64) (store.Store, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	db, ok := t.dbs[handle]
+	return db, ok
+}
+
+// remove drops handle's db entry. Its error entry, if any, is left in
+// place so a caller that hasn't yet collected LastError for a failed call
+// still can.
+func (t *handleTable) remove(handle uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.dbs, handle)
+}
+
+// setError records the last error seen for handle, overwriting whatever
+// was there before.
+func (t *handleTable) setError(handle uint64, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		delete(t.errs, handle)
+		return
+	}
+	t.errs[handle] = err.Error()
+}
+
+// lastError returns handle's last recorded error message, or "" if none.
+func (t *handleTable) lastError(handle uint64) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.errs[handle]
+}
+
+// LastError returns the most recent error recorded against handle (by any
+// of the exported functions below), or an empty string if none has been
+// recorded. A later successful call does not clear it. The returned string is heap-allocated C memory the caller
+// must release with GoFree, matching every other *C.char this package
+// returns.
+//
+//export LastError
+func LastError(handle uint64) *C.char {
+	return C.CString(handles.lastError(handle))
+}
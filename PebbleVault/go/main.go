@@ -6,11 +6,32 @@ package main
 import "C"
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
-	"log"
+	"sync"
 	"unsafe"
 
-	"github.com/tidwall/buntdb"
+	"pebble-vault/replication"
+	"pebble-vault/store"
+)
+
+// replicationHandle pairs a running replication.Node with the cancel func
+// for the context its Start was given, so CloseDB can stop its
+// runSender/runSyncSweep goroutines and listener instead of leaking them
+// past the db they were replicating.
+type replicationHandle struct {
+	node   *replication.Node
+	cancel context.CancelFunc
+}
+
+// replicationNodes tracks the replication.Node enabled for a given db
+// handle, if any. Most callers never enable replication, so this stays
+// empty and CreateGalaxy/CreateSpatialIndex are a single map lookup.
+var (
+	replicationMu    sync.Mutex
+	replicationNodes = map[uint64]*replicationHandle{}
 )
 
 //export Greet
@@ -23,38 +44,72 @@ func GoFree(ptr *C.char) {
 	C.free(unsafe.Pointer(ptr))
 }
 
+// CreateDB opens path with the named backend ("buntdb" or "bbolt", see
+// store.Backend*) and returns a handle for it, or invalidHandle on
+// failure. Callers that get invalidHandle can still retrieve the reason
+// via LastError(0). An empty backend defaults to buntdb, matching the
+// original hardcoded behavior.
+//
 //export CreateDB
-func CreateDB() uintptr {
-	// Open the data.db file. It will be created if it doesn't exist.
-	db, err := buntdb.Open("data.db")
+func CreateDB(backend *C.char, path *C.char) uint64 {
+	db, err := store.Open(C.GoString(backend), C.GoString(path))
 	if err != nil {
-		log.Fatal(err)
+		handles.setError(invalidHandle, err)
+		return invalidHandle
 	}
-	return uintptr(unsafe.Pointer(db))
-	//return uintptr(uintptr(unsafe.Pointer(db)))
+	return handles.add(db)
 }
 
 //export CloseDB
-func CloseDB(db uintptr) {
-	// Close the database when done.
-	(*buntdb.DB)(unsafe.Pointer(db)).Close()
+func CloseDB(handle uint64) int32 {
+	db, ok := handles.get(handle)
+	if !ok {
+		return errInvalidHandle
+	}
+	err := db.Close()
+	handles.remove(handle)
+	replicationMu.Lock()
+	if rh, ok := replicationNodes[handle]; ok {
+		rh.cancel()
+		delete(replicationNodes, handle)
+	}
+	replicationMu.Unlock()
+	if err != nil {
+		handles.setError(handle, err)
+		return errOperationFailed
+	}
+	return errOK
 }
 
 //export CreateSpatialIndex
-func CreateSpatialIndex(db uintptr, indexName *C.char, indexKey *C.char) {
+func CreateSpatialIndex(handle uint64, indexName *C.char, indexKey *C.char) int32 {
+	db, ok := handles.get(handle)
+	if !ok {
+		return errInvalidHandle
+	}
+
+	name, pattern := C.GoString(indexName), C.GoString(indexKey)
 	// db.CreateSpatialIndex("fleet", "fleet:*:pos", buntdb.IndexRect)
-	(*buntdb.DB)(unsafe.Pointer(db)).CreateSpatialIndex(C.GoString(indexName), C.GoString(indexKey), buntdb.IndexRect)
+	if err := db.CreateSpatialIndex(name, pattern); err != nil {
+		handles.setError(handle, err)
+		return errOperationFailed
+	}
+
+	if node := replicationNode(handle); node != nil {
+		node.Emit("__index__:"+name, pattern)
+	}
+	return errOK
 }
 
-//db.Update(func(tx *buntdb.Tx) error {
-//	tx.Set("fleet:0:pos", "[-115.567 33.532]", nil)
-//	tx.Set("fleet:1:pos", "[-116.671 35.735]", nil)
-//	tx.Set("fleet:2:pos", "[-113.902 31.234]", nil)
+//db.Update(func(tx store.Tx) error {
+//	tx.Set("fleet:0:pos", "[-115.567 33.532]")
+//	tx.Set("fleet:1:pos", "[-116.671 35.735]")
+//	tx.Set("fleet:2:pos", "[-113.902 31.234]")
 //	return nil
 //})
 
 //export CreateGalaxy
-func CreateGalaxy(db uintptr, key *C.char, value *C.char) {
+func CreateGalaxy(handle uint64, key *C.char, value *C.char) int32 {
 	/*
 		func do add Galaxy Data, made it by:
 		Transform data: {Location: {x: 0, y: 0, z: 0},
@@ -63,25 +118,102 @@ func CreateGalaxy(db uintptr, key *C.char, value *C.char) {
 		Point Data: Array of relative location vectors paired with a brightness value and a color value(RGB):
 		[{x: 0, y: 0, z: 0, brightness: 0, color: {r: 0, g: 0, b: 0}}]}
 	*/
-	(*buntdb.DB)(unsafe.Pointer(db)).Update(func(tx *buntdb.Tx) error {
-		tx.Set(C.GoString(key), C.GoString(value), nil)
-		return nil
+	db, ok := handles.get(handle)
+	if !ok {
+		return errInvalidHandle
+	}
+
+	k, v := C.GoString(key), C.GoString(value)
+	err := db.Update(func(tx store.Tx) error {
+		return tx.Set(k, v)
 	})
+	if err != nil {
+		handles.setError(handle, err)
+		return errOperationFailed
+	}
+
+	if node := replicationNode(handle); node != nil {
+		node.Emit(k, v)
+	}
+	return errOK
 }
 
 //export GetKNearestGalaxys
-func GetKNearestGalaxys(db uintptr, key *C.char) *C.char {
-	var result string
-	(*buntdb.DB)(unsafe.Pointer(db)).View(func(tx *buntdb.Tx) error {
-		tx.Nearby("galaxy", C.GoString(key), func(key, val string, dist float64) bool {
-			result += key + ":" + val + ","
-			return true
-		})
+func GetKNearestGalaxys(handle uint64, key *C.char) *C.char {
+	db, ok := handles.get(handle)
+	if !ok {
+		handles.setError(handle, fmt.Errorf("pebble-vault: invalid handle %d", handle))
 		return nil
+	}
+
+	var result string
+	db.Nearby("galaxy", C.GoString(key), func(key, val string, dist float64) bool {
+		result += key + ":" + val + ","
+		return true
 	})
 	return C.CString(result)
 }
 
+// EnableReplication starts gossiping every future CreateGalaxy/
+// CreateSpatialIndex write on handle's db to the given peers, and listens
+// on listenAddr (e.g. "0.0.0.0:7444") so those same peers can push and
+// sync back to this node; pass an empty listenAddr to only dial out.
+// peersJSON is a JSON array of {"id": "...", "addr": "host:port"}
+// objects. Replication currently only works against the buntdb backend; a
+// handle opened with the bbolt backend returns errOperationFailed.
+// Replication for handle stops, along with its goroutines and listener,
+// when handle is closed with CloseDB.
+//
+//export EnableReplication
+func EnableReplication(handle uint64, nodeID *C.char, peersJSON *C.char, listenAddr *C.char) int32 {
+	db, ok := handles.get(handle)
+	if !ok {
+		return errInvalidHandle
+	}
+	buntStore, ok := db.(*store.BuntStore)
+	if !ok {
+		handles.setError(handle, fmt.Errorf("pebble-vault: replication requires the %s backend", store.BackendBuntDB))
+		return errOperationFailed
+	}
+
+	var peers []replication.PeerConfig
+	if err := json.Unmarshal([]byte(C.GoString(peersJSON)), &peers); err != nil {
+		handles.setError(handle, fmt.Errorf("pebble-vault: parse peers: %w", err))
+		return errOperationFailed
+	}
+
+	node := replication.NewNode(replication.Config{
+		NodeID:     C.GoString(nodeID),
+		Peers:      peers,
+		TLSConfig:  &tls.Config{},
+		ListenAddr: C.GoString(listenAddr),
+	}, buntStore.Underlying())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := node.Start(ctx); err != nil {
+		cancel()
+		handles.setError(handle, fmt.Errorf("pebble-vault: start replication: %w", err))
+		return errOperationFailed
+	}
+
+	replicationMu.Lock()
+	replicationNodes[handle] = &replicationHandle{node: node, cancel: cancel}
+	replicationMu.Unlock()
+	return errOK
+}
+
+// replicationNode returns the replication.Node enabled for handle via
+// EnableReplication, or nil if replication was never turned on for it.
+func replicationNode(handle uint64) *replication.Node {
+	replicationMu.Lock()
+	defer replicationMu.Unlock()
+	rh, ok := replicationNodes[handle]
+	if !ok {
+		return nil
+	}
+	return rh.node
+}
+
 func main() {
 	// Prevent main from exiting immediately.
 	select {}
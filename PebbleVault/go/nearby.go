@@ -0,0 +1,168 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// nearbyResult is one row of a GetKNearest/NearbyIter response.
+type nearbyResult struct {
+	Key   string  `json:"key"`
+	Value string  `json:"value"`
+	Dist  float64 `json:"dist"`
+}
+
+// GetKNearest is GetKNearestGalaxys with the limits that function never
+// had: k caps the result count and maxDist caps how far a match may be
+// from key, so a large index can't blow past what the caller is willing
+// to hold. Either limit can be disabled by passing <= 0. The result is a
+// JSON array of {"key","value","dist"} objects rather than a
+// comma-separated string, so callers don't have to hand-roll parsing for
+// keys or values containing ":" or ",".
+//
+//export GetKNearest
+func GetKNearest(handle uint64, indexName *C.char, key *C.char, k int32, maxDist float64) *C.char {
+	db, ok := handles.get(handle)
+	if !ok {
+		handles.setError(handle, fmt.Errorf("pebble-vault: invalid handle %d", handle))
+		return nil
+	}
+
+	results := []nearbyResult{}
+	err := db.Nearby(C.GoString(indexName), C.GoString(key), func(k2, v string, dist float64) bool {
+		if maxDist > 0 && dist > maxDist {
+			return false
+		}
+		results = append(results, nearbyResult{Key: k2, Value: v, Dist: dist})
+		return k <= 0 || int32(len(results)) < k
+	})
+	if err != nil {
+		handles.setError(handle, err)
+		return nil
+	}
+
+	payload, err := json.Marshal(results)
+	if err != nil {
+		handles.setError(handle, fmt.Errorf("pebble-vault: encode nearby results: %w", err))
+		return nil
+	}
+	return C.CString(string(payload))
+}
+
+// nearbyRow is one hit pushed down a nearbyIter's channel, or the
+// zero-value-plus-ok-false sentinel the channel's close sends once the
+// underlying store.Store.Nearby call has finished.
+type nearbyRow struct {
+	key, value string
+	dist       float64
+}
+
+// nearbyIter is the live state behind a NearbyIter handle: a goroutine
+// runs db.Nearby and feeds rows one at a time down rows, blocking until
+// NearbyNext drains the previous one. That means a caller that stops
+// calling NearbyNext partway through never forces the rest of the index
+// into memory, unlike GetKNearest.
+type nearbyIter struct {
+	rows chan nearbyRow
+	stop chan struct{}
+	once sync.Once
+}
+
+func (it *nearbyIter) close() {
+	it.once.Do(func() { close(it.stop) })
+}
+
+var (
+	nearbyItersMu  sync.Mutex
+	nearbyIters    = map[uint64]*nearbyIter{}
+	nearbyIterNext uint64
+)
+
+// NearbyIter starts a db.Nearby walk of indexName from key in the
+// background and returns a handle NearbyNext can pull rows from one at a
+// time. Callers that don't read it to exhaustion must release it with
+// NearbyIterClose, or its goroutine blocks forever waiting to send its
+// next row.
+//
+//export NearbyIter
+func NearbyIter(handle uint64, indexName *C.char, key *C.char) uint64 {
+	db, ok := handles.get(handle)
+	if !ok {
+		handles.setError(handle, fmt.Errorf("pebble-vault: invalid handle %d", handle))
+		return invalidHandle
+	}
+
+	it := &nearbyIter{rows: make(chan nearbyRow), stop: make(chan struct{})}
+
+	nearbyItersMu.Lock()
+	nearbyIterNext++
+	id := nearbyIterNext
+	nearbyIters[id] = it
+	nearbyItersMu.Unlock()
+
+	name, origin := C.GoString(indexName), C.GoString(key)
+	go func() {
+		defer close(it.rows)
+		db.Nearby(name, origin, func(k, v string, dist float64) bool {
+			select {
+			case it.rows <- nearbyRow{key: k, value: v, dist: dist}:
+				return true
+			case <-it.stop:
+				return false
+			}
+		})
+	}()
+
+	return id
+}
+
+// NearbyNext pulls the next row from iterID into *outKey/*outVal/
+// *outDist and returns 1, or returns 0 once the walk is exhausted (and
+// releases iterID automatically) or errInvalidHandle if iterID is
+// unknown. outKey and outVal are heap-allocated C strings the caller must
+// release with GoFree, same as every other *C.char this package hands
+// out.
+//
+//export NearbyNext
+func NearbyNext(iterID uint64, outKey **C.char, outVal **C.char, outDist *float64) int32 {
+	nearbyItersMu.Lock()
+	it, ok := nearbyIters[iterID]
+	nearbyItersMu.Unlock()
+	if !ok {
+		return errInvalidHandle
+	}
+
+	row, ok := <-it.rows
+	if !ok {
+		nearbyItersMu.Lock()
+		delete(nearbyIters, iterID)
+		nearbyItersMu.Unlock()
+		return 0
+	}
+
+	*outKey = C.CString(row.key)
+	*outVal = C.CString(row.value)
+	*outDist = row.dist
+	return 1
+}
+
+// NearbyIterClose releases iterID before it's been read to exhaustion,
+// stopping its goroutine and dropping its entry. Calling it on an
+// already-exhausted or unknown iterID is a harmless no-op.
+//
+//export NearbyIterClose
+func NearbyIterClose(iterID uint64) {
+	nearbyItersMu.Lock()
+	it, ok := nearbyIters[iterID]
+	delete(nearbyIters, iterID)
+	nearbyItersMu.Unlock()
+	if ok {
+		it.close()
+	}
+}
@@ -0,0 +1,322 @@
+// Package replication gossips galaxy/fleet writes between Horizon nodes so
+// spatial data can be sharded across more than one process. Each node owns
+// one buntdb.DB; writes are pushed to every configured peer over a bounded
+// queue, and a periodic full-sync sweep repairs anything a peer missed
+// while it was down or partitioned.
+package replication
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tidwall/buntdb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"pebble-vault/replication/pb"
+)
+
+// seenKeyPrefix namespaces the secondary "when was this last written"
+// bookkeeping buntdb keeps alongside the real value, so last-writer-wins
+// merges don't need a second database.
+const seenKeyPrefix = "__replication_seen__:"
+
+// ReplicationRecord is a single key/value write stamped with the
+// wall-clock time, in Unix nanoseconds, of the node that produced it. The
+// stamp is not forced to increase: it goes backwards if that node's clock
+// is stepped back.
+type ReplicationRecord struct {
+	Key   string
+	Value string
+	Seen  int64
+}
+
+// PeerConfig is a statically configured replication peer.
+type PeerConfig struct {
+	ID   string
+	Addr string
+}
+
+// Config controls a Node's peers and timing. Peers are discovered purely
+// from static configuration; there is no gossip-based membership protocol.
+type Config struct {
+	NodeID       string
+	Peers        []PeerConfig
+	QueueSize    int           // per-peer bounded send queue, default 1024
+	SyncInterval time.Duration // full-sync sweep period, default 30s
+	TLSConfig    *tls.Config
+
+	// ListenAddr is where this node accepts incoming Push/Sync/Announce
+	// calls from its peers, e.g. ":7444". Leave empty to only dial out
+	// (useful in tests), but then no peer can ever reach this node back.
+	ListenAddr string
+}
+
+type peer struct {
+	cfg       PeerConfig
+	queue     chan ReplicationRecord
+	lastAcked int64 // unix-nano cursor, updated after a successful Sync pull
+	conn      *grpc.ClientConn
+	client    pb.ReplicationClient
+}
+
+// Node drives replication for one buntdb.DB against a fixed set of peers.
+type Node struct {
+	cfg   Config
+	db    *buntdb.DB
+	peers map[string]*peer
+	mu    sync.Mutex
+}
+
+// NewNode wires a replication Node around db. The caller retains ownership
+// of db and must call Start to begin gossiping.
+func NewNode(cfg Config, db *buntdb.DB) *Node {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.SyncInterval <= 0 {
+		cfg.SyncInterval = 30 * time.Second
+	}
+	n := &Node{cfg: cfg, db: db, peers: make(map[string]*peer, len(cfg.Peers))}
+	for _, pc := range cfg.Peers {
+		n.peers[pc.ID] = &peer{cfg: pc, queue: make(chan ReplicationRecord, cfg.QueueSize)}
+	}
+	return n
+}
+
+// Start dials every configured peer, begins the background sender and
+// periodic full-sync sweep for each, and — if cfg.ListenAddr is set —
+// listens for incoming Push/Sync/Announce calls from those same peers.
+// Everything Start launches is tied to ctx: cancelling it stops the
+// sender/sweep goroutines and shuts down the listener. It returns once
+// all peers have been announced to and the listener (if any) is up, or
+// ctx is cancelled first.
+func (n *Node) Start(ctx context.Context) error {
+	if n.cfg.ListenAddr != "" {
+		lis, err := net.Listen("tcp", n.cfg.ListenAddr)
+		if err != nil {
+			return fmt.Errorf("replication: listen on %s: %w", n.cfg.ListenAddr, err)
+		}
+		grpcServer := grpc.NewServer()
+		pb.RegisterReplicationServer(grpcServer, NewServer(n))
+		go func() {
+			<-ctx.Done()
+			grpcServer.GracefulStop()
+		}()
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Printf("replication: serve %s: %v", n.cfg.ListenAddr, err)
+			}
+		}()
+	}
+
+	creds := credentials.NewTLS(n.cfg.TLSConfig)
+	for _, p := range n.peers {
+		conn, err := grpc.NewClient(p.cfg.Addr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return fmt.Errorf("replication: dial peer %s: %w", p.cfg.ID, err)
+		}
+		p.conn = conn
+		p.client = pb.NewReplicationClient(conn)
+
+		if _, err := p.client.Announce(ctx, &pb.AnnounceRequest{NodeId: n.cfg.NodeID}); err != nil {
+			log.Printf("replication: announce to peer %s failed: %v", p.cfg.ID, err)
+		}
+
+		go n.runSender(ctx, p)
+		go n.runSyncSweep(ctx, p)
+	}
+	return nil
+}
+
+// Emit enqueues a key/value write for replication to every peer, and
+// stamps the same seenKeyPrefix bookkeeping merge() would, so a
+// locally-originated write is just as visible to a peer's later Sync
+// pull as one merge() itself received. Without that stamp only writes
+// this node first heard about from a peer ever show up in its own Sync
+// responses. It should be called after the equivalent local buntdb write
+// has committed. A full queue drops the oldest pending record for that
+// peer rather than blocking the caller, since the periodic sync sweep
+// will repair gaps.
+func (n *Node) Emit(key, value string) {
+	record := ReplicationRecord{Key: key, Value: value, Seen: time.Now().UnixNano()}
+	n.stampSeen(key, record.Seen)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, p := range n.peers {
+		select {
+		case p.queue <- record:
+		default:
+			select {
+			case <-p.queue:
+			default:
+			}
+			select {
+			case p.queue <- record:
+			default:
+			}
+		}
+	}
+}
+
+// stampSeen records seen as key's last-write timestamp in the same
+// bookkeeping merge() maintains, so runSyncSweep's AscendKeys scan and
+// Server.Sync can find it.
+func (n *Node) stampSeen(key string, seen int64) {
+	err := n.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(seenKeyPrefix+key, fmt.Sprintf("%d", seen), nil)
+		return err
+	})
+	if err != nil {
+		log.Printf("replication: stamp %s: %v", key, err)
+	}
+}
+
+// runSender drains a peer's send queue into a Push stream, reconnecting on
+// failure.
+func (n *Node) runSender(ctx context.Context, p *peer) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case record := <-p.queue:
+			stream, err := p.client.Push(ctx)
+			if err != nil {
+				log.Printf("replication: push stream to peer %s: %v", p.cfg.ID, err)
+				continue
+			}
+			if err := stream.Send(&pb.Record{Key: record.Key, Value: record.Value, Seen: record.Seen}); err != nil {
+				log.Printf("replication: push record to peer %s: %v", p.cfg.ID, err)
+				continue
+			}
+			if _, err := stream.CloseAndRecv(); err != nil {
+				log.Printf("replication: push ack from peer %s: %v", p.cfg.ID, err)
+			}
+		}
+	}
+}
+
+// runSyncSweep periodically pulls every record the peer has newer than our
+// last-acked cursor for it, merging each one locally. This is what repairs
+// a peer's view after a dropped connection or a missed Push.
+func (n *Node) runSyncSweep(ctx context.Context, p *peer) {
+	ticker := time.NewTicker(n.cfg.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			since := atomic.LoadInt64(&p.lastAcked)
+			stream, err := p.client.Sync(ctx, &pb.SyncRequest{NodeId: n.cfg.NodeID, Since: since})
+			if err != nil {
+				log.Printf("replication: sync sweep against peer %s: %v", p.cfg.ID, err)
+				continue
+			}
+			var newest int64
+			complete := false
+			for {
+				rec, err := stream.Recv()
+				if err != nil {
+					complete = err == io.EOF
+					break
+				}
+				n.merge(rec.Key, rec.Value, rec.Seen)
+				if rec.Seen > newest {
+					newest = rec.Seen
+				}
+			}
+			if complete && newest > since {
+				atomic.StoreInt64(&p.lastAcked, newest)
+			}
+		}
+	}
+}
+
+// merge applies a remote write using last-writer-wins: it only overwrites
+// the local value if seen is newer than what we already have recorded.
+func (n *Node) merge(key, value string, seen int64) {
+	err := n.db.Update(func(tx *buntdb.Tx) error {
+		localSeen, err := tx.Get(seenKeyPrefix + key)
+		if err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+		if err == nil {
+			var existing int64
+			fmt.Sscanf(localSeen, "%d", &existing)
+			if existing >= seen {
+				return nil
+			}
+		}
+		if _, _, err := tx.Set(key, value, nil); err != nil {
+			return err
+		}
+		_, _, err = tx.Set(seenKeyPrefix+key, fmt.Sprintf("%d", seen), nil)
+		return err
+	})
+	if err != nil {
+		log.Printf("replication: merge %s: %v", key, err)
+	}
+}
+
+// Server implements pb.ReplicationServer, accepting Push/Sync traffic from
+// peers and feeding it through the same merge() last-writer-wins path.
+type Server struct {
+	pb.UnimplementedReplicationServer
+
+	node *Node
+}
+
+// NewServer exposes node's merge path to incoming peer connections.
+func NewServer(node *Node) *Server {
+	return &Server{node: node}
+}
+
+func (s *Server) Announce(ctx context.Context, req *pb.AnnounceRequest) (*pb.AnnounceResponse, error) {
+	return &pb.AnnounceResponse{NodeId: s.node.cfg.NodeID}, nil
+}
+
+func (s *Server) Push(stream pb.Replication_PushServer) error {
+	var accepted int64
+	for {
+		rec, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		s.node.merge(rec.Key, rec.Value, rec.Seen)
+		accepted++
+	}
+	return stream.SendAndClose(&pb.PushSummary{Accepted: accepted})
+}
+
+// Sync scans the local seenKeyPrefix bookkeeping keys and streams every
+// key whose recorded seen timestamp is newer than req.Since, with its
+// current value.
+func (s *Server) Sync(req *pb.SyncRequest, stream pb.Replication_SyncServer) error {
+	return s.node.db.View(func(tx *buntdb.Tx) error {
+		var sendErr error
+		tx.AscendKeys(seenKeyPrefix+"*", func(seenKey, seenVal string) bool {
+			var seen int64
+			fmt.Sscanf(seenVal, "%d", &seen)
+			if seen <= req.Since {
+				return true
+			}
+			key := seenKey[len(seenKeyPrefix):]
+			val, err := tx.Get(key)
+			if err != nil {
+				return true
+			}
+			sendErr = stream.Send(&pb.Record{Key: key, Value: val, Seen: seen})
+			return sendErr == nil
+		})
+		return sendErr
+	})
+}
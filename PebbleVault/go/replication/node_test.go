@@ -0,0 +1,83 @@
+package replication
+
+import (
+	"testing"
+
+	"github.com/tidwall/buntdb"
+)
+
+func newTestNode(t *testing.T) (*Node, *buntdb.DB) {
+	t.Helper()
+	db, err := buntdb.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open buntdb: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewNode(Config{NodeID: "test"}, db), db
+}
+
+func getValue(t *testing.T, db *buntdb.DB, key string) (string, error) {
+	t.Helper()
+	var val string
+	err := db.View(func(tx *buntdb.Tx) error {
+		var err error
+		val, err = tx.Get(key)
+		return err
+	})
+	return val, err
+}
+
+func TestMergeLastWriterWins(t *testing.T) {
+	n, db := newTestNode(t)
+
+	n.merge("galaxy:1:pos", "[1 1]", 100)
+	if val, err := getValue(t, db, "galaxy:1:pos"); err != nil || val != "[1 1]" {
+		t.Fatalf("after first merge: %q, %v, want \"[1 1]\", nil", val, err)
+	}
+
+	// An older write must not overwrite a newer one.
+	n.merge("galaxy:1:pos", "[2 2]", 50)
+	if val, err := getValue(t, db, "galaxy:1:pos"); err != nil || val != "[1 1]" {
+		t.Fatalf("after stale merge: %q, %v, want \"[1 1]\", nil (stale write should be rejected)", val, err)
+	}
+
+	// A newer write must win.
+	n.merge("galaxy:1:pos", "[3 3]", 150)
+	if val, err := getValue(t, db, "galaxy:1:pos"); err != nil || val != "[3 3]" {
+		t.Fatalf("after newer merge: %q, %v, want \"[3 3]\", nil", val, err)
+	}
+}
+
+func TestEmitStampsSeenForLocalWrites(t *testing.T) {
+	n, db := newTestNode(t)
+
+	// Emit is called for a locally-originated write, the same way
+	// CreateGalaxy/CreateSpatialIndex call it after their own db.Update
+	// has already committed the value.
+	if err := db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set("galaxy:1:pos", "[1 1]", nil)
+		return err
+	}); err != nil {
+		t.Fatalf("seed local write: %v", err)
+	}
+	n.Emit("galaxy:1:pos", "[1 1]")
+
+	// A peer's Sync pull (Server.Sync) finds repairable writes by
+	// scanning seenKeyPrefix+"*". A locally-originated write that never
+	// stamped that bookkeeping key would be invisible to it.
+	var found bool
+	err := db.View(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(seenKeyPrefix+"*", func(key, val string) bool {
+			if key == seenKeyPrefix+"galaxy:1:pos" {
+				found = true
+			}
+			return true
+		})
+	})
+	if err != nil {
+		t.Fatalf("scan seen keys: %v", err)
+	}
+	if !found {
+		t.Fatal("Emit did not stamp a seenKeyPrefix entry for its local write; a peer's Sync sweep could never repair it")
+	}
+}
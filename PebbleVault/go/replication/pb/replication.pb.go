@@ -0,0 +1,65 @@
+// Hand-written to match replication.proto's wire format, not real
+// protoc-gen-go output: these types only implement the legacy v1
+// Reset/String/ProtoMessage trio (via gRPC's v1-adapter shim), not
+// protoreflect.ProtoMessage, and they lack the descriptor bytes real
+// generated code carries. Running protoc-gen-go against replication.proto
+// will produce different, incompatible types — update this file by hand
+// instead.
+// source: replication.proto
+
+package pb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+type Record struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Seen  int64  `protobuf:"varint,3,opt,name=seen,proto3" json:"seen,omitempty"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+
+type AnnounceRequest struct {
+	NodeId string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+}
+
+func (m *AnnounceRequest) Reset()         { *m = AnnounceRequest{} }
+func (m *AnnounceRequest) String() string { return proto.CompactTextString(m) }
+func (*AnnounceRequest) ProtoMessage()    {}
+
+type AnnounceResponse struct {
+	NodeId string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+}
+
+func (m *AnnounceResponse) Reset()         { *m = AnnounceResponse{} }
+func (m *AnnounceResponse) String() string { return proto.CompactTextString(m) }
+func (*AnnounceResponse) ProtoMessage()    {}
+
+type SyncRequest struct {
+	NodeId string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Since  int64  `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (m *SyncRequest) Reset()         { *m = SyncRequest{} }
+func (m *SyncRequest) String() string { return proto.CompactTextString(m) }
+func (*SyncRequest) ProtoMessage()    {}
+
+type PushSummary struct {
+	Accepted int64 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+}
+
+func (m *PushSummary) Reset()         { *m = PushSummary{} }
+func (m *PushSummary) String() string { return proto.CompactTextString(m) }
+func (*PushSummary) ProtoMessage()    {}
+
+func init() {
+	proto.RegisterType((*Record)(nil), "replication.Record")
+	proto.RegisterType((*AnnounceRequest)(nil), "replication.AnnounceRequest")
+	proto.RegisterType((*AnnounceResponse)(nil), "replication.AnnounceResponse")
+	proto.RegisterType((*SyncRequest)(nil), "replication.SyncRequest")
+	proto.RegisterType((*PushSummary)(nil), "replication.PushSummary")
+}
@@ -0,0 +1,206 @@
+// Hand-written to match replication.proto's service definition, not real
+// protoc-gen-go-grpc output. See replication.pb.go for why; keep this
+// file and that one in sync by hand when the .proto changes.
+// source: replication.proto
+
+package pb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+const (
+	Replication_Announce_FullMethodName = "/replication.Replication/Announce"
+	Replication_Push_FullMethodName     = "/replication.Replication/Push"
+	Replication_Sync_FullMethodName     = "/replication.Replication/Sync"
+)
+
+type ReplicationClient interface {
+	Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AnnounceResponse, error)
+	Push(ctx context.Context, opts ...grpc.CallOption) (Replication_PushClient, error)
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (Replication_SyncClient, error)
+}
+
+type replicationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReplicationClient(cc grpc.ClientConnInterface) ReplicationClient {
+	return &replicationClient{cc}
+}
+
+func (c *replicationClient) Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AnnounceResponse, error) {
+	out := new(AnnounceResponse)
+	if err := c.cc.Invoke(ctx, Replication_Announce_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *replicationClient) Push(ctx context.Context, opts ...grpc.CallOption) (Replication_PushClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Replication_ServiceDesc.Streams[0], Replication_Push_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &replicationPushClient{stream}, nil
+}
+
+type Replication_PushClient interface {
+	Send(*Record) error
+	CloseAndRecv() (*PushSummary, error)
+	grpc.ClientStream
+}
+
+type replicationPushClient struct {
+	grpc.ClientStream
+}
+
+func (x *replicationPushClient) Send(m *Record) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *replicationPushClient) CloseAndRecv() (*PushSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PushSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *replicationClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (Replication_SyncClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Replication_ServiceDesc.Streams[1], Replication_Sync_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &replicationSyncClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Replication_SyncClient interface {
+	Recv() (*Record, error)
+	grpc.ClientStream
+}
+
+type replicationSyncClient struct {
+	grpc.ClientStream
+}
+
+func (x *replicationSyncClient) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type ReplicationServer interface {
+	Announce(context.Context, *AnnounceRequest) (*AnnounceResponse, error)
+	Push(Replication_PushServer) error
+	Sync(*SyncRequest, Replication_SyncServer) error
+	mustEmbedUnimplementedReplicationServer()
+}
+
+type UnimplementedReplicationServer struct{}
+
+func (UnimplementedReplicationServer) Announce(context.Context, *AnnounceRequest) (*AnnounceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Announce not implemented")
+}
+func (UnimplementedReplicationServer) Push(Replication_PushServer) error {
+	return status.Errorf(codes.Unimplemented, "method Push not implemented")
+}
+func (UnimplementedReplicationServer) Sync(*SyncRequest, Replication_SyncServer) error {
+	return status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (UnimplementedReplicationServer) mustEmbedUnimplementedReplicationServer() {}
+
+func RegisterReplicationServer(s grpc.ServiceRegistrar, srv ReplicationServer) {
+	s.RegisterService(&Replication_ServiceDesc, srv)
+}
+
+func _Replication_Announce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReplicationServer).Announce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Replication_Announce_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReplicationServer).Announce(ctx, req.(*AnnounceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Replication_Push_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ReplicationServer).Push(&replicationPushServer{stream})
+}
+
+type Replication_PushServer interface {
+	SendAndClose(*PushSummary) error
+	Recv() (*Record, error)
+	grpc.ServerStream
+}
+
+type replicationPushServer struct {
+	grpc.ServerStream
+}
+
+func (x *replicationPushServer) SendAndClose(m *PushSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *replicationPushServer) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Replication_Sync_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SyncRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReplicationServer).Sync(m, &replicationSyncServer{stream})
+}
+
+type Replication_SyncServer interface {
+	Send(*Record) error
+	grpc.ServerStream
+}
+
+type replicationSyncServer struct {
+	grpc.ServerStream
+}
+
+func (x *replicationSyncServer) Send(m *Record) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var Replication_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "replication.Replication",
+	HandlerType: (*ReplicationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Announce", Handler: _Replication_Announce_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Push", Handler: _Replication_Push_Handler, ClientStreams: true},
+		{StreamName: "Sync", Handler: _Replication_Sync_Handler, ServerStreams: true},
+	},
+	Metadata: "replication.proto",
+}
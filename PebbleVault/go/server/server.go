@@ -0,0 +1,180 @@
+// Package server is the Socket.IO front end for the spatial vault. Unlike
+// the original package main it replaced, a Server is given one open
+// store.Store and keeps it for the whole process lifetime instead of
+// opening one per request, and Run exits cleanly when its context is cancelled instead of
+// blocking forever in http.ListenAndServe.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	socketio "github.com/googollee/go-socket.io"
+
+	"pebble-vault/store"
+)
+
+// namespaces are the spatial index namespaces a connection can claim,
+// mapped to the key pattern CreateSpatialIndex registers for it. New
+// namespaces go here as Horizon needs them.
+var namespaces = map[string]string{
+	"galaxy": "galaxy:*:pos",
+	"fleet":  "fleet:*:pos",
+}
+
+// defaultNamespace is what a connection is scoped to until it sends a
+// "claim" event for a different one.
+const defaultNamespace = "galaxy"
+
+// session is the per-connection state stashed with Conn.SetContext. It
+// lets the "update" and "nearby" handlers know which spatial index
+// namespace a connection is scoped to without threading it through every
+// event payload.
+type session struct {
+	namespace string
+}
+
+// nearbyResult is one row of a "nearby" response.
+type nearbyResult struct {
+	Key   string  `json:"key"`
+	Value string  `json:"value"`
+	Dist  float64 `json:"dist"`
+}
+
+// Server serves the spatial vault over Socket.IO against a single
+// store.Store shared by every connection.
+type Server struct {
+	db  store.Store
+	sio *socketio.Server
+	srv *http.Server
+}
+
+// New opens db's spatial indexes (see namespaces) and wires the Socket.IO
+// handlers against it. The Server takes ownership of db: the caller must
+// not close it, since Run closes it on shutdown.
+func New(addr string, db store.Store) (*Server, error) {
+	for name, pattern := range namespaces {
+		if err := db.CreateSpatialIndex(name, pattern); err != nil {
+			return nil, fmt.Errorf("server: create spatial index %s: %w", name, err)
+		}
+	}
+
+	sio := socketio.NewServer(nil)
+
+	sio.OnConnect("/", func(c socketio.Conn) error {
+		c.SetContext(&session{namespace: defaultNamespace})
+		log.Println("Connected:", c.ID())
+		return nil
+	})
+
+	sio.OnDisconnect("/", func(c socketio.Conn, reason string) {
+		log.Println("Disconnected:", c.ID(), reason)
+	})
+
+	// "claim" rescopes this connection's subsequent update/nearby events
+	// to a different spatial index namespace, e.g. "fleet" instead of
+	// the default "galaxy".
+	sio.OnEvent("/", "claim", func(c socketio.Conn, namespace string) {
+		if _, ok := namespaces[namespace]; !ok {
+			c.Emit("claimError", fmt.Sprintf("unknown namespace %q", namespace))
+			return
+		}
+		c.SetContext(&session{namespace: namespace})
+	})
+
+	sio.OnEvent("/", "update", func(c socketio.Conn, key, value string) {
+		sess := sessionOf(c)
+		fullKey := fmt.Sprintf("%s:%s:pos", sess.namespace, key)
+
+		start := time.Now()
+		err := db.Update(func(tx store.Tx) error {
+			return tx.Set(fullKey, value)
+		})
+		if err != nil {
+			log.Println("Error in update transaction:", err)
+			return
+		}
+		c.Emit("updateResult", fmt.Sprintf("Update transaction took: %s", time.Since(start)))
+	})
+
+	sio.OnEvent("/", "nearby", func(c socketio.Conn, origin string) {
+		sess := sessionOf(c)
+
+		var results []nearbyResult
+		err := db.Nearby(sess.namespace, origin, func(key, value string, dist float64) bool {
+			results = append(results, nearbyResult{Key: key, Value: value, Dist: dist})
+			return true
+		})
+		if err != nil {
+			log.Println("Error in nearby query:", err)
+			return
+		}
+
+		payload, err := json.Marshal(results)
+		if err != nil {
+			log.Println("Error encoding nearby results:", err)
+			return
+		}
+		c.Emit("nearbyResult", string(payload))
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/socket.io/", sio)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "Go server is up and running!")
+	})
+
+	return &Server{
+		db:  db,
+		sio: sio,
+		srv: &http.Server{Addr: addr, Handler: mux},
+	}, nil
+}
+
+// sessionOf returns the session OnConnect stashed on c, falling back to
+// defaultNamespace if a handler somehow runs before OnConnect has.
+func sessionOf(c socketio.Conn) *session {
+	if sess, ok := c.Context().(*session); ok {
+		return sess
+	}
+	return &session{namespace: defaultNamespace}
+}
+
+// Run serves Socket.IO and HTTP until ctx is cancelled or one of them
+// fails, then drains Socket.IO connections, shuts down the HTTP listener,
+// and closes db.
+func (s *Server) Run(ctx context.Context) error {
+	errc := make(chan error, 2)
+	go func() { errc <- s.sio.Serve() }()
+	go func() {
+		if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errc <- err
+			return
+		}
+		errc <- nil
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-errc:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.srv.Shutdown(shutdownCtx); err != nil && runErr == nil {
+		runErr = err
+	}
+	if err := s.sio.Close(); err != nil && runErr == nil {
+		runErr = err
+	}
+	if err := s.db.Close(); err != nil && runErr == nil {
+		runErr = err
+	}
+	return runErr
+}
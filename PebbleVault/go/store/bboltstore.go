@@ -0,0 +1,295 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tidwall/match"
+	"github.com/tidwall/rtred"
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketKV = []byte("kv")
+
+// BboltStore is the durable backend: every mutation is journaled to a
+// bbolt bucket keyed by its galaxy/fleet key, and the spatial index lives
+// entirely in memory as an rtred.RTree rebuilt from that journal on open.
+// That keeps Nearby as fast as BuntStore's while surviving a restart.
+type BboltStore struct {
+	db *bolt.DB
+
+	mu      sync.RWMutex
+	values  map[string]string
+	indexes map[string]*spatialIndex
+}
+
+type spatialIndex struct {
+	keyPattern string
+	tree       *rtred.RTree
+	items      map[string]*rtreeItem // key -> the *rtreeItem it was Inserted with, so Remove can find it again
+}
+
+// rtreeItem is the rtred.Item a spatialIndex inserts: a single point
+// (min == max) tagged with the key it came from, so a KNN hit can be
+// mapped back to the value in BboltStore.values.
+type rtreeItem struct {
+	key string
+	pt  [2]float64
+}
+
+func (it *rtreeItem) Rect(ctx interface{}) (min, max []float64) {
+	return it.pt[:], it.pt[:]
+}
+
+// OpenBboltStore opens (creating if necessary) a bbolt-backed store at
+// path and replays its journal to rebuild the in-memory spatial indexes.
+// CreateSpatialIndex must still be called after opening to know which
+// keys belong to which index, same as with BuntStore.
+func OpenBboltStore(path string) (*BboltStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("store: open bbolt at %s: %w", path, err)
+	}
+
+	s := &BboltStore{
+		db:      db,
+		values:  make(map[string]string),
+		indexes: make(map[string]*spatialIndex),
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketKV)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := s.replay(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay loads every key/value bbolt already has on disk back into the
+// in-memory mirror. It runs once, at open.
+func (s *BboltStore) replay() error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketKV).ForEach(func(k, v []byte) error {
+			s.values[string(k)] = string(v)
+			return nil
+		})
+	})
+}
+
+func (s *BboltStore) CreateSpatialIndex(indexName, keyPattern string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := &spatialIndex{
+		keyPattern: keyPattern,
+		tree:       rtred.New(nil),
+		items:      make(map[string]*rtreeItem),
+	}
+	s.indexes[indexName] = idx
+
+	// Back-fill from whatever replay() already loaded, in case
+	// CreateSpatialIndex runs after keys matching it were journaled by an
+	// earlier process.
+	for key, value := range s.values {
+		if match.Match(key, keyPattern) {
+			s.indexKey(idx, key, value)
+		}
+	}
+	return nil
+}
+
+func (s *BboltStore) indexKey(idx *spatialIndex, key, value string) {
+	pt, ok := parsePoint(value)
+	if !ok {
+		return
+	}
+	if old, had := idx.items[key]; had {
+		idx.tree.Remove(old)
+	}
+	item := &rtreeItem{key: key, pt: pt}
+	idx.items[key] = item
+	idx.tree.Insert(item)
+}
+
+func (s *BboltStore) unindexKey(key string) {
+	for _, idx := range s.indexes {
+		if item, ok := idx.items[key]; ok {
+			idx.tree.Remove(item)
+			delete(idx.items, key)
+		}
+	}
+}
+
+func (s *BboltStore) View(fn func(tx Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(bboltReadTx{store: s})
+}
+
+func (s *BboltStore) Update(fn func(tx Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ops []kvOp
+	if err := fn(&bboltWriteTx{store: s, ops: &ops, overlay: make(map[string]*string)}); err != nil {
+		return err
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketKV)
+		for _, op := range ops {
+			switch op.kind {
+			case opSet:
+				if err := b.Put([]byte(op.key), []byte(op.value)); err != nil {
+					return err
+				}
+			case opDelete:
+				if err := b.Delete([]byte(op.key)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only mirror the ops once bbolt has committed them.
+	for _, op := range ops {
+		switch op.kind {
+		case opSet:
+			s.values[op.key] = op.value
+			for _, idx := range s.indexes {
+				if match.Match(op.key, idx.keyPattern) {
+					s.indexKey(idx, op.key, op.value)
+				}
+			}
+		case opDelete:
+			delete(s.values, op.key)
+			s.unindexKey(op.key)
+		}
+	}
+	return nil
+}
+
+func (s *BboltStore) Nearby(indexName, origin string, iter NearbyIterator) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	idx, ok := s.indexes[indexName]
+	if !ok {
+		return fmt.Errorf("store: unknown spatial index %q", indexName)
+	}
+	from, ok := parsePoint(origin)
+	if !ok {
+		return fmt.Errorf("store: invalid rect %q", origin)
+	}
+
+	// KNN walks idx.tree nearest-to-farthest already, so there's no need
+	// to collect and sort every point by hand. dist is the squared box
+	// distance, which is also what buntdb.Tx.Nearby reports for points, so
+	// it is passed through unchanged.
+	idx.tree.KNN(&rtreeItem{pt: from}, false, func(hit rtred.Item, sqDist float64) bool {
+		item := hit.(*rtreeItem)
+		return iter(item.key, s.values[item.key], sqDist)
+	})
+	return nil
+}
+
+func (s *BboltStore) Close() error {
+	return s.db.Close()
+}
+
+type opKind int
+
+const (
+	opSet opKind = iota
+	opDelete
+)
+
+type kvOp struct {
+	kind  opKind
+	key   string
+	value string
+}
+
+type bboltReadTx struct {
+	store *BboltStore
+}
+
+func (t bboltReadTx) Get(key string) (string, error) {
+	val, ok := t.store.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return val, nil
+}
+
+func (t bboltReadTx) Set(key, value string) error {
+	return fmt.Errorf("store: Set called in a read-only transaction")
+}
+
+func (t bboltReadTx) Delete(key string) error {
+	return fmt.Errorf("store: Delete called in a read-only transaction")
+}
+
+// bboltWriteTx stages Set/Delete as kvOps to apply to bbolt and the
+// in-memory mirror atomically once Update's fn returns successfully, but
+// keeps its own overlay of those pending writes (nil value means a
+// pending delete) so Get sees them in the meantime — matching BuntStore,
+// where a Get immediately after a Set in the same Update callback sees
+// the new value rather than whatever was there before the transaction.
+type bboltWriteTx struct {
+	store   *BboltStore
+	ops     *[]kvOp
+	overlay map[string]*string
+}
+
+func (t *bboltWriteTx) Get(key string) (string, error) {
+	if v, staged := t.overlay[key]; staged {
+		if v == nil {
+			return "", ErrNotFound
+		}
+		return *v, nil
+	}
+	val, ok := t.store.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return val, nil
+}
+
+func (t *bboltWriteTx) Set(key, value string) error {
+	t.overlay[key] = &value
+	*t.ops = append(*t.ops, kvOp{kind: opSet, key: key, value: value})
+	return nil
+}
+
+func (t *bboltWriteTx) Delete(key string) error {
+	if _, err := t.Get(key); err != nil {
+		return err
+	}
+	t.overlay[key] = nil
+	*t.ops = append(*t.ops, kvOp{kind: opDelete, key: key})
+	return nil
+}
+
+// parsePoint parses a buntdb-style rect string, e.g. "[-115.567 33.532]",
+// into a 2D point. Only single-point rects are supported, which is all
+// CreateGalaxy/SetFleetPosition ever write.
+func parsePoint(rect string) (pt [2]float64, ok bool) {
+	var x, y float64
+	if _, err := fmt.Sscanf(rect, "[%g %g]", &x, &y); err != nil {
+		return pt, false
+	}
+	return [2]float64{x, y}, true
+}
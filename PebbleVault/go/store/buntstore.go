@@ -0,0 +1,82 @@
+package store
+
+import "github.com/tidwall/buntdb"
+
+// BuntStore is the original backend: everything lives in buntdb, which
+// already gives us spatial indexing and Nearby for free. It's the right
+// choice for ":memory:" or single-process use; see BboltStore for a
+// backend that survives a restart.
+type BuntStore struct {
+	db *buntdb.DB
+}
+
+// OpenBuntStore opens path with buntdb.Open. Use ":memory:" for a
+// non-persistent store.
+func OpenBuntStore(path string) (*BuntStore, error) {
+	db, err := buntdb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BuntStore{db: db}, nil
+}
+
+func (s *BuntStore) View(fn func(tx Tx) error) error {
+	return s.db.View(func(tx *buntdb.Tx) error {
+		return fn(buntTx{tx})
+	})
+}
+
+func (s *BuntStore) Update(fn func(tx Tx) error) error {
+	return s.db.Update(func(tx *buntdb.Tx) error {
+		return fn(buntTx{tx})
+	})
+}
+
+func (s *BuntStore) CreateSpatialIndex(indexName, keyPattern string) error {
+	return s.db.CreateSpatialIndex(indexName, keyPattern, buntdb.IndexRect)
+}
+
+func (s *BuntStore) Nearby(indexName, origin string, iter NearbyIterator) error {
+	return s.db.View(func(tx *buntdb.Tx) error {
+		return tx.Nearby(indexName, origin, func(key, val string, dist float64) bool {
+			return iter(key, val, dist)
+		})
+	})
+}
+
+func (s *BuntStore) Close() error {
+	return s.db.Close()
+}
+
+// Underlying exposes the wrapped *buntdb.DB for callers that need
+// buntdb-specific behavior the Store interface doesn't cover, such as the
+// replication package's last-writer-wins merge transactions.
+func (s *BuntStore) Underlying() *buntdb.DB {
+	return s.db
+}
+
+// buntTx adapts *buntdb.Tx to the Tx interface.
+type buntTx struct {
+	tx *buntdb.Tx
+}
+
+func (t buntTx) Get(key string) (string, error) {
+	val, err := t.tx.Get(key)
+	if err == buntdb.ErrNotFound {
+		return "", ErrNotFound
+	}
+	return val, err
+}
+
+func (t buntTx) Set(key, value string) error {
+	_, _, err := t.tx.Set(key, value, nil)
+	return err
+}
+
+func (t buntTx) Delete(key string) error {
+	_, err := t.tx.Delete(key)
+	if err == buntdb.ErrNotFound {
+		return ErrNotFound
+	}
+	return err
+}
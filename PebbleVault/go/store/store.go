@@ -0,0 +1,62 @@
+// Package store abstracts the spatial key/value store behind the cgo and
+// Socket.IO layers so they can run against either of two backends: the
+// original in-memory/on-disk buntdb, or a bbolt-journaled store that
+// survives a process restart. Callers that only need View/Update/Nearby
+// don't need to know which one they got.
+package store
+
+import "errors"
+
+// ErrNotFound matches buntdb.ErrNotFound so callers can compare against a
+// single sentinel regardless of backend.
+var ErrNotFound = errors.New("store: key not found")
+
+// Tx is the read/write view handed to View and Update callbacks.
+type Tx interface {
+	// Get returns the value for key, or ErrNotFound.
+	Get(key string) (string, error)
+	// Set stores value under key.
+	Set(key, value string) error
+	// Delete removes key, returning ErrNotFound if it wasn't present.
+	Delete(key string) error
+}
+
+// NearbyIterator is called once per match, nearest first, by Store.Nearby.
+// Returning false stops iteration early.
+type NearbyIterator func(key, value string, dist float64) bool
+
+// Store is the spatial key/value abstraction both CreateDB's backend
+// selector and the Socket.IO handlers are written against.
+type Store interface {
+	// View runs fn in a read-only transaction.
+	View(fn func(tx Tx) error) error
+	// Update runs fn in a read/write transaction.
+	Update(fn func(tx Tx) error) error
+	// CreateSpatialIndex registers indexName so Nearby can later query
+	// keys matching keyPattern by their rect-encoded value.
+	CreateSpatialIndex(indexName, keyPattern string) error
+	// Nearby walks indexName's entries in ascending distance from
+	// origin (a buntdb-style rect string, e.g. "[-115.567 33.532]").
+	Nearby(indexName, origin string, iter NearbyIterator) error
+	// Close releases the backend's underlying resources.
+	Close() error
+}
+
+// Backend names accepted by Open.
+const (
+	BackendBuntDB = "buntdb"
+	BackendBbolt  = "bbolt"
+)
+
+// Open opens the named backend at path. backend must be BackendBuntDB or
+// BackendBbolt; an empty backend selects BackendBuntDB.
+func Open(backend, path string) (Store, error) {
+	switch backend {
+	case BackendBuntDB, "":
+		return OpenBuntStore(path)
+	case BackendBbolt:
+		return OpenBboltStore(path)
+	default:
+		return nil, errors.New("store: unknown backend " + backend)
+	}
+}
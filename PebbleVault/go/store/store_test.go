@@ -0,0 +1,188 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// backends is every Store implementation CreateDB can hand out, so CRUD
+// and Nearby behavior get exercised against both instead of just the one
+// a test happens to be written against.
+func backends(t *testing.T) map[string]Store {
+	t.Helper()
+	bunt, err := OpenBuntStore(":memory:")
+	if err != nil {
+		t.Fatalf("open buntdb: %v", err)
+	}
+	t.Cleanup(func() { bunt.Close() })
+
+	bolt, err := OpenBboltStore(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("open bbolt: %v", err)
+	}
+	t.Cleanup(func() { bolt.Close() })
+
+	return map[string]Store{
+		"buntdb": bunt,
+		"bbolt":  bolt,
+	}
+}
+
+func TestStoreCRUD(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := db.Update(func(tx Tx) error { return tx.Set("k", "v") }); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+
+			var got string
+			err := db.View(func(tx Tx) error {
+				var err error
+				got, err = tx.Get("k")
+				return err
+			})
+			if err != nil || got != "v" {
+				t.Fatalf("Get after Set = %q, %v, want \"v\", nil", got, err)
+			}
+
+			if err := db.Update(func(tx Tx) error { return tx.Delete("k") }); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			err = db.View(func(tx Tx) error {
+				_, err := tx.Get("k")
+				return err
+			})
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+			}
+
+			err = db.Update(func(tx Tx) error { return tx.Delete("k") })
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("second Delete = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+// TestStoreGetSeesOwnWritesInUpdate guards against a Tx implementation
+// that stages Set/Delete for later and only applies them once the whole
+// Update callback returns: a Get for the same key later in that same
+// callback must already see the pending write, not the pre-transaction
+// value.
+func TestStoreGetSeesOwnWritesInUpdate(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := db.Update(func(tx Tx) error { return tx.Set("k", "old") }); err != nil {
+				t.Fatalf("seed Set: %v", err)
+			}
+
+			var gotAfterSet, gotAfterDelete string
+			var deleteErr error
+			err := db.Update(func(tx Tx) error {
+				if err := tx.Set("k", "new"); err != nil {
+					return err
+				}
+				var err error
+				gotAfterSet, err = tx.Get("k")
+				if err != nil {
+					return err
+				}
+
+				if err := tx.Delete("k"); err != nil {
+					return err
+				}
+				gotAfterDelete, deleteErr = tx.Get("k")
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if gotAfterSet != "new" {
+				t.Errorf("Get right after Set in the same Update = %q, want \"new\"", gotAfterSet)
+			}
+			if !errors.Is(deleteErr, ErrNotFound) {
+				t.Errorf("Get right after Delete in the same Update = %q, %v, want ErrNotFound", gotAfterDelete, deleteErr)
+			}
+		})
+	}
+}
+
+func TestStoreNearby(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := db.CreateSpatialIndex("galaxy", "galaxy:*:pos"); err != nil {
+				t.Fatalf("CreateSpatialIndex: %v", err)
+			}
+
+			points := map[string]string{
+				"galaxy:near:pos": "[1 0]",
+				"galaxy:mid:pos":  "[3 0]",
+				"galaxy:far:pos":  "[10 0]",
+			}
+			err := db.Update(func(tx Tx) error {
+				for key, value := range points {
+					if err := tx.Set(key, value); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+
+			var gotKeys []string
+			var gotDists []float64
+			err = db.Nearby("galaxy", "[0 0]", func(key, value string, dist float64) bool {
+				gotKeys = append(gotKeys, key)
+				gotDists = append(gotDists, dist)
+				return true
+			})
+			if err != nil {
+				t.Fatalf("Nearby: %v", err)
+			}
+
+			wantKeys := []string{"galaxy:near:pos", "galaxy:mid:pos", "galaxy:far:pos"}
+			if len(gotKeys) != len(wantKeys) {
+				t.Fatalf("Nearby returned %d keys, want %d: %v", len(gotKeys), len(wantKeys), gotKeys)
+			}
+			for i, want := range wantKeys {
+				if gotKeys[i] != want {
+					t.Errorf("Nearby()[%d] = %q, want %q (got order %v)", i, gotKeys[i], want, gotKeys)
+				}
+			}
+			for i := 1; i < len(gotDists); i++ {
+				if gotDists[i] < gotDists[i-1] {
+					t.Errorf("Nearby distances not ascending: %v", gotDists)
+				}
+			}
+
+			// Stopping early (returning false) must stop the walk, not
+			// just skip emitting further results.
+			var calls int
+			err = db.Nearby("galaxy", "[0 0]", func(key, value string, dist float64) bool {
+				calls++
+				return false
+			})
+			if err != nil {
+				t.Fatalf("Nearby (early stop): %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("Nearby called iter %d times after returning false, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestStoreNearbyUnknownIndex(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			err := db.Nearby("nope", "[0 0]", func(key, value string, dist float64) bool { return true })
+			if err == nil {
+				t.Fatal("Nearby on an unregistered index returned nil error, want one")
+			}
+		})
+	}
+}